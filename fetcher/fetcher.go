@@ -0,0 +1,58 @@
+// Package fetcher abstracts access to a remote log source so Portal isn't
+// tied to plain HTTP Range requests. The scheme of a source URL picks the
+// implementation: http(s):// (default), sftp://, s3://, or
+// k8s://namespace/pod[/container].
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// Fetcher reads from one remote log source.
+type Fetcher interface {
+	// Size returns the source's current size and an opaque version token
+	// (ETag, generation, mtime, ...) used to detect out-of-band
+	// replacement of the source. The token may be empty if the source
+	// doesn't expose one.
+	Size(ctx context.Context) (int64, string, error)
+
+	// ReadRange writes bytes [start, end) to w. Sources that can't do
+	// partial reads (e.g. streaming pod logs) may ignore start/end and
+	// instead write everything new since the last call.
+	ReadRange(ctx context.Context, start int64, end int64, w io.Writer) error
+}
+
+type constructor func(rawURL string) (Fetcher, error)
+
+var registry = map[string]constructor{}
+
+// register makes a Fetcher constructor available under a URL scheme.
+// Called from the init() function of each concrete implementation.
+func register(scheme string, newFunc constructor) {
+	registry[scheme] = newFunc
+}
+
+// New parses rawURL's scheme and constructs the matching Fetcher. A URL
+// with no scheme is treated as plain http(s), matching this tool's
+// historical behavior of taking a bare "host/path/to/file" URL.
+func New(rawURL string) (Fetcher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse source url: %v", err)
+	}
+
+	scheme := u.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	newFunc, ok := registry[scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported source scheme: %s", scheme)
+	}
+
+	return newFunc(rawURL)
+}
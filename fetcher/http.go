@@ -0,0 +1,75 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+func init() {
+	register("http", newHTTPFetcher)
+	register("https", newHTTPFetcher)
+}
+
+// HTTPFetcher reads a source exposed over plain HTTP(S) via Range
+// requests, the original (and still default) behavior of this tool.
+type HTTPFetcher struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPFetcher(rawURL string) (Fetcher, error) {
+	return &HTTPFetcher{url: rawURL, client: http.DefaultClient}, nil
+}
+
+// SetClient overrides the http.Client used for requests, e.g. so a
+// portal.Manager can share one client with connection pooling across many
+// Portals.
+func (f *HTTPFetcher) SetClient(client *http.Client) {
+	f.client = client
+}
+
+func (f *HTTPFetcher) Size(ctx context.Context) (int64, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, f.url, nil)
+	if err != nil {
+		return 0, "", fmt.Errorf("create new request: %v", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("http head: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return resp.ContentLength, resp.Header.Get("ETag"), nil
+}
+
+func (f *HTTPFetcher) ReadRange(ctx context.Context, start int64, end int64, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return fmt.Errorf("create new request: %v", err)
+	}
+
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("copy response body: %v", err)
+	}
+
+	return nil
+}
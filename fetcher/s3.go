@@ -0,0 +1,91 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func init() {
+	register("s3", newS3Fetcher)
+}
+
+// S3Fetcher reads an object from an S3-compatible bucket, using
+// HeadObject for size and a Range GetObject for ranged reads.
+type S3Fetcher struct {
+	bucket string
+	key    string
+	client *s3.Client
+}
+
+// newS3Fetcher builds a fetcher from a URL of the form s3://bucket/key.
+// Credentials and region are resolved the standard AWS way (environment,
+// shared config, instance role, ...).
+func newS3Fetcher(rawURL string) (Fetcher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse s3 url: %v", err)
+	}
+
+	key := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || key == "" {
+		return nil, fmt.Errorf("s3 url must be of the form s3://bucket/key, got %q", rawURL)
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %v", err)
+	}
+
+	return &S3Fetcher{
+		bucket: u.Host,
+		key:    key,
+		client: s3.NewFromConfig(cfg),
+	}, nil
+}
+
+func (f *S3Fetcher) Size(ctx context.Context) (int64, string, error) {
+	out, err := f.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(f.bucket),
+		Key:    aws.String(f.key),
+	})
+	if err != nil {
+		return 0, "", fmt.Errorf("head object[%s/%s]: %v", f.bucket, f.key, err)
+	}
+
+	size := int64(0)
+	if out.ContentLength != nil {
+		size = *out.ContentLength
+	}
+
+	etag := ""
+	if out.ETag != nil {
+		etag = *out.ETag
+	}
+
+	return size, etag, nil
+}
+
+func (f *S3Fetcher) ReadRange(ctx context.Context, start int64, end int64, w io.Writer) error {
+	out, err := f.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(f.bucket),
+		Key:    aws.String(f.key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", start, end-1)),
+	})
+	if err != nil {
+		return fmt.Errorf("get object[%s/%s]: %v", f.bucket, f.key, err)
+	}
+	defer out.Body.Close()
+
+	if _, err := io.Copy(w, out.Body); err != nil {
+		return fmt.Errorf("copy object body: %v", err)
+	}
+
+	return nil
+}
@@ -3,13 +3,29 @@ package main
 import (
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
+	"git.uqpaytech.com/xufeng/log-portal/manager"
 	"git.uqpaytech.com/xufeng/log-portal/portal"
 )
 
+// repeatedFlag collects the values of a flag that may be passed more than
+// once on the command line, e.g. `-include-field a -include-field b`.
+type repeatedFlag []string
+
+func (r *repeatedFlag) String() string {
+	return strings.Join(*r, ",")
+}
+
+func (r *repeatedFlag) Set(value string) error {
+	*r = append(*r, value)
+	return nil
+}
+
 func main() {
 	// mandatory
 	fileURL := flag.String("u", "", "Log file URL.")
@@ -23,8 +39,34 @@ func main() {
 	enableFile := flag.Bool("enablefile", false, "Enable write log into file.")
 	disableConsole := flag.Bool("diableconsole", false, "Disable output log on the console.")
 
+	// optional structured parsing
+	format := flag.String("format", "raw", "Output format for parsed lines: raw, json or logfmt.")
+	parserName := flag.String("parser", "", "Line parser to decode raw log lines with (e.g. nginx, apache, syslog, regexp). Required unless --format is raw.")
+	parserPattern := flag.String("parser-pattern", "", "Regular expression with named capture groups, used when --parser is regexp.")
+	var includeFields repeatedFlag
+	flag.Var(&includeFields, "include-field", "Only keep this parsed field in the output; may be repeated.")
+	var excludeFields repeatedFlag
+	flag.Var(&excludeFields, "exclude-field", "Drop this parsed field from the output; may be repeated.")
+
+	var sinks repeatedFlag
+	flag.Var(&sinks, "sink", "External destination to ship output to (loki://, es://, kafka://, s3://); may be repeated.")
+
+	verifyInterval := flag.Int64("verify-interval", 0, "Re-probe the last 4KiB written against the remote source every N fetch ticks to detect silent rotation; 0 disables verification.")
+
+	maxTotalSize := flag.Int64("max-total-size", 0, "Total size budget in bytes for archived logs; oldest archives are evicted first once exceeded. 0 disables size-based eviction.")
+	compressFormat := flag.String("compress", portal.CompressNone, "Compression for rotated archives: none, gzip or zstd.")
+
+	// optional: follow many sources at once
+	config := flag.String("config", "", "Path to a YAML/TOML Manager config file to follow many sources concurrently; when set, -u/-d/... are ignored.")
+	metricsAddr := flag.String("metrics-addr", ":9090", "Listen address for the Prometheus /metrics endpoint in -config mode.")
+
 	flag.Parse()
 
+	if *config != "" {
+		runManager(*config, *metricsAddr)
+		return
+	}
+
 	if *fileURL == "" {
 		fmt.Println("Please provide log file URL.")
 		return
@@ -57,11 +99,34 @@ func main() {
 		return
 	}
 
+	if *format != "raw" {
+		if err := ptl.SetupParser(*format, *parserName, *parserPattern, includeFields, excludeFields); err != nil {
+			fmt.Printf("Failed to setup parser: %v.\n", err)
+			return
+		}
+	}
+
 	if err := ptl.SetupWriter(*disableConsole, *enableFile, *dir, *lifetime); err != nil {
 		fmt.Printf("Failed to setup writer: %v.\n", err)
 		return
 	}
 
+	if len(sinks) > 0 {
+		if err := ptl.SetupSinks(sinks); err != nil {
+			fmt.Printf("Failed to setup sinks: %v.\n", err)
+			return
+		}
+	}
+
+	if *verifyInterval > 0 {
+		ptl.EnableVerification(*verifyInterval)
+	}
+
+	if err := ptl.SetupRetention(*maxTotalSize, *compressFormat, nil); err != nil {
+		fmt.Printf("Failed to setup retention: %v.\n", err)
+		return
+	}
+
 	defer ptl.Finalize()
 
 	signals := make(chan os.Signal, 1)
@@ -72,3 +137,39 @@ func main() {
 	sig := <-signals
 	fmt.Printf("\n\n[Portal]: received signal[%v] and exiting...", sig)
 }
+
+// runManager loads a Manager config file and follows every source it
+// describes concurrently until a termination signal arrives.
+func runManager(configPath string, metricsAddr string) {
+	cfg, err := manager.LoadConfig(configPath)
+	if err != nil {
+		fmt.Printf("Failed to load manager config: %v.\n", err)
+		return
+	}
+
+	mgr := manager.New(cfg.Sources)
+
+	for _, src := range cfg.Sources {
+		if err := mgr.AddSource(src); err != nil {
+			fmt.Printf("Failed to add source[%s]: %v.\n", src.URL, err)
+			return
+		}
+	}
+
+	go func() {
+		http.Handle("/metrics", mgr.MetricsHandler())
+		if err := http.ListenAndServe(metricsAddr, nil); err != nil {
+			fmt.Printf("[Manager]: metrics server: %v\n", err)
+		}
+	}()
+
+	mgr.Start()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+
+	sig := <-signals
+	fmt.Printf("\n\n[Manager]: received signal[%v] and exiting...", sig)
+
+	mgr.Shutdown()
+}
@@ -0,0 +1,58 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// SourceConfig describes one remote log file to follow.
+type SourceConfig struct {
+	URL      string            `yaml:"url" toml:"url"`
+	Dir      string            `yaml:"dir" toml:"dir"`
+	Interval int64             `yaml:"interval" toml:"interval"`
+	Tail     int64             `yaml:"tail" toml:"tail"`
+	Labels   map[string]string `yaml:"labels" toml:"labels"`
+}
+
+// Config is the top-level shape of a Manager config file.
+type Config struct {
+	Sources []SourceConfig `yaml:"sources" toml:"sources"`
+}
+
+// LoadConfig reads a YAML (.yaml/.yml) or TOML (.toml) file into a Config.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config[%s]: %v", path, err)
+	}
+
+	var cfg Config
+
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("unmarshal yaml config: %v", err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("unmarshal toml config: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config extension: %s", ext)
+	}
+
+	for i, src := range cfg.Sources {
+		if src.URL == "" {
+			return nil, fmt.Errorf("sources[%d]: url is required", i)
+		}
+		if src.Interval <= 0 {
+			cfg.Sources[i].Interval = 2
+		}
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,76 @@
+package manager
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"git.uqpaytech.com/xufeng/log-portal/portal"
+)
+
+// metricDef pairs a gauge name/help with the Portal accessor it reads live
+// on every scrape, rather than polling on a timer.
+type metricDef struct {
+	name string
+	help string
+	fn   func(*portal.Portal) float64
+}
+
+var metricDefs = []metricDef{
+	{"portal_bytes_fetched", "Cumulative bytes fetched from the remote source.", func(p *portal.Portal) float64 { return float64(p.BytesFetched()) }},
+	{"portal_fetch_errors", "Cumulative failed size/fetch requests.", func(p *portal.Portal) float64 { return float64(p.FetchErrors()) }},
+	{"portal_rotations", "Cumulative local file rotations.", func(p *portal.Portal) float64 { return float64(p.Rotations()) }},
+	{"portal_lag_bytes", "Bytes observed on the remote source not yet fetched locally.", func(p *portal.Portal) float64 { return float64(p.Lag()) }},
+}
+
+// sourceMetrics is one supervised Portal plus the label values it reports
+// under, aligned positionally with metricsCollector.labelNames.
+type sourceMetrics struct {
+	portal      *portal.Portal
+	labelValues []string
+}
+
+// metricsCollector implements prometheus.Collector for every supervised
+// Portal's metrics. It uses a single *prometheus.Desc per metric name, built
+// from labelNames - the union of "url" and every configured source's label
+// keys - so sources that declare different label keys (e.g. one "env", one
+// "team") all register under one consistent schema instead of each trying to
+// register its own ConstLabels set, which Prometheus requires to match
+// exactly per metric name. A source missing a given key reports "" for it.
+type metricsCollector struct {
+	labelNames []string
+	descs      []*prometheus.Desc
+	sources    []sourceMetrics
+}
+
+func newMetricsCollector(labelNames []string) *metricsCollector {
+	descs := make([]*prometheus.Desc, len(metricDefs))
+	for i, md := range metricDefs {
+		descs[i] = prometheus.NewDesc(md.name, md.help, labelNames, nil)
+	}
+
+	return &metricsCollector{labelNames: labelNames, descs: descs}
+}
+
+// add registers p to be scraped under labels, keyed by labelNames; any key
+// in labelNames absent from labels reports as "".
+func (c *metricsCollector) add(p *portal.Portal, labels map[string]string) {
+	values := make([]string, len(c.labelNames))
+	for i, name := range c.labelNames {
+		values[i] = labels[name]
+	}
+
+	c.sources = append(c.sources, sourceMetrics{portal: p, labelValues: values})
+}
+
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range c.descs {
+		ch <- d
+	}
+}
+
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, sm := range c.sources {
+		for i, md := range metricDefs {
+			ch <- prometheus.MustNewConstMetric(c.descs[i], prometheus.GaugeValue, md.fn(sm.portal), sm.labelValues...)
+		}
+	}
+}
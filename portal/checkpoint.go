@@ -0,0 +1,67 @@
+package portal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const checkpointFilename = ".portal-state.json"
+
+// checkpointState is the on-disk resume state for one Portal, persisted to
+// <dir>/.portal-state.json after every successful fetch so a restart
+// doesn't re-download the tail or miss bytes written during downtime.
+type checkpointState struct {
+	Offset   int64  `json:"offset"`
+	Filename string `json:"filename"`
+
+	// ETag is the opaque version token reported by the source's Fetcher,
+	// used to detect out-of-band replacement of the remote file; see
+	// resumeCheckpoint.
+	ETag string `json:"etag,omitempty"`
+}
+
+func checkpointPath(dir string) string {
+	return filepath.Join(dir, checkpointFilename)
+}
+
+// readCheckpointFile returns nil, nil if no checkpoint exists yet.
+func readCheckpointFile(dir string) (*checkpointState, error) {
+	data, err := os.ReadFile(checkpointPath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read checkpoint: %v", err)
+	}
+
+	var state checkpointState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("unmarshal checkpoint: %v", err)
+	}
+
+	return &state, nil
+}
+
+// writeCheckpointFile writes state atomically (write to a temp file, then
+// rename) so a crash mid-write never leaves a truncated checkpoint behind.
+func writeCheckpointFile(dir string, state checkpointState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint: %v", err)
+	}
+
+	path := checkpointPath(dir)
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write checkpoint: %v", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("rename checkpoint: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package portal
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported values for Portal.CompressFormat.
+const (
+	CompressNone = "none"
+	CompressGzip = "gzip"
+	CompressZstd = "zstd"
+)
+
+// runCompressionWorker compresses rotated archives enqueued by RotateFile,
+// one at a time, until Stop is called.
+func (p *Portal) runCompressionWorker() {
+	for {
+		select {
+		case <-p.stop:
+			return
+		case path := <-p.compressQueue:
+			if err := p.compressArchive(path); err != nil {
+				fmt.Printf("compress archive[%s]: %v\n", path, err)
+			}
+		}
+	}
+}
+
+// compressArchive compresses path in place according to p.CompressFormat,
+// replacing it with a .gz/.zst sibling and removing the uncompressed file.
+func (p *Portal) compressArchive(path string) error {
+	var suffix string
+	var newWriter func(io.Writer) (io.WriteCloser, error)
+
+	switch p.CompressFormat {
+	case CompressGzip:
+		suffix = ".gz"
+		newWriter = func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil }
+	case CompressZstd:
+		suffix = ".zst"
+		newWriter = func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) }
+	default:
+		return nil
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open archive: %v", err)
+	}
+	defer in.Close()
+
+	outPath := path + suffix
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("create compressed archive: %v", err)
+	}
+
+	cw, err := newWriter(out)
+	if err != nil {
+		out.Close()
+		return fmt.Errorf("create compressor: %v", err)
+	}
+
+	if _, err := io.Copy(cw, in); err != nil {
+		cw.Close()
+		out.Close()
+		return fmt.Errorf("compress archive: %v", err)
+	}
+
+	if err := cw.Close(); err != nil {
+		out.Close()
+		return fmt.Errorf("close compressor: %v", err)
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("close compressed archive: %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("remove uncompressed archive: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package portal
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestCompressArchive(t *testing.T) {
+	content := []byte("line one\nline two\nline three\n")
+
+	cases := []struct {
+		name    string
+		format  string
+		wantSfx string
+		decode  func(t *testing.T, path string) []byte
+	}{
+		{
+			name:    "gzip",
+			format:  CompressGzip,
+			wantSfx: ".gz",
+			decode: func(t *testing.T, path string) []byte {
+				f, err := os.Open(path)
+				if err != nil {
+					t.Fatalf("open %s: %v", path, err)
+				}
+				defer f.Close()
+
+				r, err := gzip.NewReader(f)
+				if err != nil {
+					t.Fatalf("gzip reader: %v", err)
+				}
+				defer r.Close()
+
+				data, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatalf("read gzip: %v", err)
+				}
+				return data
+			},
+		},
+		{
+			name:    "zstd",
+			format:  CompressZstd,
+			wantSfx: ".zst",
+			decode: func(t *testing.T, path string) []byte {
+				f, err := os.Open(path)
+				if err != nil {
+					t.Fatalf("open %s: %v", path, err)
+				}
+				defer f.Close()
+
+				r, err := zstd.NewReader(f)
+				if err != nil {
+					t.Fatalf("zstd reader: %v", err)
+				}
+				defer r.Close()
+
+				data, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatalf("read zstd: %v", err)
+				}
+				return data
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "access-20240110T000000.000.log")
+
+			if err := os.WriteFile(path, content, 0644); err != nil {
+				t.Fatalf("write archive: %v", err)
+			}
+
+			p := &Portal{CompressFormat: tc.format}
+
+			if err := p.compressArchive(path); err != nil {
+				t.Fatalf("compressArchive: %v", err)
+			}
+
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("expected uncompressed archive %s to be removed, stat err = %v", path, err)
+			}
+
+			compressedPath := path + tc.wantSfx
+			if _, err := os.Stat(compressedPath); err != nil {
+				t.Fatalf("expected compressed archive %s to exist: %v", compressedPath, err)
+			}
+
+			got := tc.decode(t, compressedPath)
+			if string(got) != string(content) {
+				t.Errorf("decompressed content = %q, want %q", got, content)
+			}
+		})
+	}
+}
+
+func TestCompressArchive_NoneLeavesFileUntouched(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access-20240110T000000.000.log")
+	content := []byte("line one\n")
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatalf("write archive: %v", err)
+	}
+
+	p := &Portal{CompressFormat: CompressNone}
+
+	if err := p.compressArchive(path); err != nil {
+		t.Fatalf("compressArchive: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+
+	if string(got) != string(content) {
+		t.Errorf("archive content changed: got %q, want %q", got, content)
+	}
+}
@@ -0,0 +1,84 @@
+package portal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported values for Portal.OutputFormat.
+const (
+	FormatRaw    = "raw"
+	FormatJSON   = "json"
+	FormatLogfmt = "logfmt"
+)
+
+// encodeFields renders fields according to format, appending a trailing
+// newline so output stays line-delimited.
+func encodeFields(format string, fields map[string]any) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		b, err := json.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("marshal json: %v", err)
+		}
+		return append(b, '\n'), nil
+	case FormatLogfmt:
+		return append(encodeLogfmt(fields), '\n'), nil
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// encodeLogfmt renders fields as key=value pairs in a stable (sorted) key
+// order, quoting values that contain spaces, quotes or '='.
+func encodeLogfmt(fields map[string]any) []byte {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(fields[k]))
+	}
+
+	return []byte(b.String())
+}
+
+// filterFields restricts fields to the include set (when non-empty) and then
+// drops any key present in the exclude set.
+func filterFields(fields map[string]any, include map[string]bool, exclude map[string]bool) map[string]any {
+	if len(include) == 0 && len(exclude) == 0 {
+		return fields
+	}
+
+	filtered := make(map[string]any, len(fields))
+	for k, v := range fields {
+		if len(include) > 0 && !include[k] {
+			continue
+		}
+		if exclude[k] {
+			continue
+		}
+		filtered[k] = v
+	}
+
+	return filtered
+}
+
+func logfmtValue(v any) string {
+	s := fmt.Sprintf("%v", v)
+	if strings.ContainsAny(s, " \"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
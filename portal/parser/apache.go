@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+func init() {
+	Register("apache", func() Parser { return &ApacheParser{} })
+}
+
+// apacheCombined matches the Apache httpd "combined" LogFormat:
+//
+//	%h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-agent}i"
+var apacheCombined = regexp.MustCompile(`^(\S+) (\S+) (\S+) \[([^\]]+)\] "(\S+) (\S*) (\S+)" (\d{3}) (\d+|-) "([^"]*)" "([^"]*)"`)
+
+// ApacheParser decodes lines in Apache httpd's "combined" LogFormat.
+type ApacheParser struct{}
+
+func (p *ApacheParser) Parse(line []byte) (map[string]any, error) {
+	m := apacheCombined.FindSubmatch(line)
+	if m == nil {
+		return nil, fmt.Errorf("line does not match apache combined format")
+	}
+
+	status, err := strconv.Atoi(string(m[8]))
+	if err != nil {
+		return nil, fmt.Errorf("parse status: %v", err)
+	}
+
+	fields := map[string]any{
+		"host":       string(m[1]),
+		"ident":      string(m[2]),
+		"authuser":   string(m[3]),
+		"time_local": string(m[4]),
+		"method":     string(m[5]),
+		"path":       string(m[6]),
+		"protocol":   string(m[7]),
+		"status":     status,
+		"referer":    string(m[10]),
+		"user_agent": string(m[11]),
+	}
+
+	if bytes := string(m[9]); bytes != "-" {
+		n, err := strconv.Atoi(bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse bytes sent: %v", err)
+		}
+		fields["bytes"] = n
+	} else {
+		fields["bytes"] = 0
+	}
+
+	return fields, nil
+}
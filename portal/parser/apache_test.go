@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApacheParser_Parse(t *testing.T) {
+	cases := []struct {
+		name    string
+		line    string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "valid combined line",
+			line: `127.0.0.1 - bob [10/Oct/2023:13:55:36 +0000] "GET /index.html HTTP/1.1" 200 2326 "http://example.com/" "Mozilla/5.0"`,
+			want: map[string]any{
+				"host":       "127.0.0.1",
+				"ident":      "-",
+				"authuser":   "bob",
+				"time_local": "10/Oct/2023:13:55:36 +0000",
+				"method":     "GET",
+				"path":       "/index.html",
+				"protocol":   "HTTP/1.1",
+				"status":     200,
+				"bytes":      2326,
+				"referer":    "http://example.com/",
+				"user_agent": "Mozilla/5.0",
+			},
+		},
+		{
+			name: "dash bytes",
+			line: `127.0.0.1 - - [10/Oct/2023:13:55:36 +0000] "GET / HTTP/1.1" 304 - "-" "-"`,
+			want: map[string]any{
+				"host":       "127.0.0.1",
+				"ident":      "-",
+				"authuser":   "-",
+				"time_local": "10/Oct/2023:13:55:36 +0000",
+				"method":     "GET",
+				"path":       "/",
+				"protocol":   "HTTP/1.1",
+				"status":     304,
+				"bytes":      0,
+				"referer":    "-",
+				"user_agent": "-",
+			},
+		},
+		{
+			name:    "non-matching line",
+			line:    "this is not an apache access log line",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &ApacheParser{}
+
+			got, err := p.Parse([]byte(tc.line))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = nil error, want error", tc.line)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tc.line, err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Parse(%q) = %#v, want %#v", tc.line, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+func init() {
+	Register("nginx", func() Parser { return &NginxParser{} })
+}
+
+// nginxCombined matches the default nginx "combined" log_format:
+//
+//	$remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent "$http_referer" "$http_user_agent"
+var nginxCombined = regexp.MustCompile(`^(\S+) - (\S+) \[([^\]]+)\] "(\S+) (\S*) (\S+)" (\d{3}) (\d+|-) "([^"]*)" "([^"]*)"`)
+
+// NginxParser decodes lines in nginx's default "combined" log_format.
+type NginxParser struct{}
+
+func (p *NginxParser) Parse(line []byte) (map[string]any, error) {
+	m := nginxCombined.FindSubmatch(line)
+	if m == nil {
+		return nil, fmt.Errorf("line does not match nginx combined format")
+	}
+
+	status, err := strconv.Atoi(string(m[7]))
+	if err != nil {
+		return nil, fmt.Errorf("parse status: %v", err)
+	}
+
+	fields := map[string]any{
+		"remote_addr": string(m[1]),
+		"remote_user": string(m[2]),
+		"time_local":  string(m[3]),
+		"method":      string(m[4]),
+		"path":        string(m[5]),
+		"protocol":    string(m[6]),
+		"status":      status,
+		"referer":     string(m[9]),
+		"user_agent":  string(m[10]),
+	}
+
+	if bytes := string(m[8]); bytes != "-" {
+		n, err := strconv.Atoi(bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse body_bytes_sent: %v", err)
+		}
+		fields["body_bytes_sent"] = n
+	} else {
+		fields["body_bytes_sent"] = 0
+	}
+
+	return fields, nil
+}
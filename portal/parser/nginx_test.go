@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNginxParser_Parse(t *testing.T) {
+	cases := []struct {
+		name    string
+		line    string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "valid combined line",
+			line: `127.0.0.1 - alice [10/Oct/2023:13:55:36 +0000] "GET /index.html HTTP/1.1" 200 612 "http://example.com/" "curl/7.81.0"`,
+			want: map[string]any{
+				"remote_addr":     "127.0.0.1",
+				"remote_user":     "alice",
+				"time_local":      "10/Oct/2023:13:55:36 +0000",
+				"method":          "GET",
+				"path":            "/index.html",
+				"protocol":        "HTTP/1.1",
+				"status":          200,
+				"body_bytes_sent": 612,
+				"referer":         "http://example.com/",
+				"user_agent":      "curl/7.81.0",
+			},
+		},
+		{
+			name: "dash body bytes sent",
+			line: `127.0.0.1 - - [10/Oct/2023:13:55:36 +0000] "GET / HTTP/1.1" 200 - "-" "-"`,
+			want: map[string]any{
+				"remote_addr":     "127.0.0.1",
+				"remote_user":     "-",
+				"time_local":      "10/Oct/2023:13:55:36 +0000",
+				"method":          "GET",
+				"path":            "/",
+				"protocol":        "HTTP/1.1",
+				"status":          200,
+				"body_bytes_sent": 0,
+				"referer":         "-",
+				"user_agent":      "-",
+			},
+		},
+		{
+			name:    "non-matching line",
+			line:    "this is not an nginx access log line",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &NginxParser{}
+
+			got, err := p.Parse([]byte(tc.line))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = nil error, want error", tc.line)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tc.line, err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Parse(%q) = %#v, want %#v", tc.line, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Package parser decodes raw remote log lines into structured fields so
+// Portal can re-emit them as JSON or logfmt instead of raw bytes.
+package parser
+
+import "fmt"
+
+// Parser turns a single raw log line into a set of named fields.
+type Parser interface {
+	// Parse decodes one line (without its trailing newline) into fields.
+	Parse(line []byte) (map[string]any, error)
+}
+
+var registry = map[string]func() Parser{}
+
+// Register makes a parser constructor available under name so it can be
+// selected via Portal.ParserName / the --parser flag. Register is normally
+// called from an init() function of the package implementing the parser.
+func Register(name string, newFunc func() Parser) {
+	registry[name] = newFunc
+}
+
+// Lookup returns a fresh Parser instance registered under name.
+func Lookup(name string) (Parser, bool) {
+	newFunc, ok := registry[name]
+	if !ok {
+		return nil, false
+	}
+	return newFunc(), true
+}
+
+// New is a convenience wrapper around Lookup that returns an error instead
+// of a boolean, matching the error-return convention used elsewhere in this
+// repo.
+func New(name string) (Parser, error) {
+	p, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown parser: %s", name)
+	}
+	return p, nil
+}
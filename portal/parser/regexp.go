@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// RegexParser decodes lines using a user-supplied regular expression whose
+// named capture groups become the resulting fields, e.g.
+// `(?P<ip>\S+) (?P<status>\d{3})`.
+type RegexParser struct {
+	re *regexp.Regexp
+}
+
+// NewRegexParser compiles pattern and validates that it declares at least
+// one named capture group.
+func NewRegexParser(pattern string) (*RegexParser, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compile pattern: %v", err)
+	}
+
+	named := false
+	for i, name := range re.SubexpNames() {
+		if i != 0 && name != "" {
+			named = true
+			break
+		}
+	}
+	if !named {
+		return nil, fmt.Errorf("pattern must declare at least one named capture group")
+	}
+
+	return &RegexParser{re: re}, nil
+}
+
+func (p *RegexParser) Parse(line []byte) (map[string]any, error) {
+	m := p.re.FindSubmatch(line)
+	if m == nil {
+		return nil, fmt.Errorf("line does not match regex pattern")
+	}
+
+	fields := make(map[string]any)
+	for i, name := range p.re.SubexpNames() {
+		if i == 0 || name == "" {
+			continue
+		}
+		fields[name] = string(m[i])
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewRegexParser(t *testing.T) {
+	cases := []struct {
+		name    string
+		pattern string
+		wantErr bool
+	}{
+		{name: "named group", pattern: `(?P<ip>\S+) (?P<status>\d{3})`},
+		{name: "no groups at all", pattern: `\S+ \d{3}`, wantErr: true},
+		{name: "unnamed group only", pattern: `(\S+) (\d{3})`, wantErr: true},
+		{name: "invalid pattern", pattern: `(`, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewRegexParser(tc.pattern)
+			if tc.wantErr && err == nil {
+				t.Fatalf("NewRegexParser(%q) = nil error, want error", tc.pattern)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("NewRegexParser(%q) unexpected error: %v", tc.pattern, err)
+			}
+		})
+	}
+}
+
+func TestRegexParser_Parse(t *testing.T) {
+	p, err := NewRegexParser(`(?P<ip>\S+) (?P<status>\d{3})`)
+	if err != nil {
+		t.Fatalf("NewRegexParser: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		line    string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "matching line",
+			line: "127.0.0.1 200",
+			want: map[string]any{"ip": "127.0.0.1", "status": "200"},
+		},
+		{
+			name:    "non-matching line",
+			line:    "not a match",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := p.Parse([]byte(tc.line))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = nil error, want error", tc.line)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tc.line, err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Parse(%q) = %#v, want %#v", tc.line, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+func init() {
+	Register("syslog", func() Parser { return &SyslogParser{} })
+}
+
+// syslogRFC5424 matches RFC 5424 formatted messages:
+//
+//	<PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+var syslogRFC5424 = regexp.MustCompile(`^<(\d{1,3})>(\d+) (\S+) (\S+) (\S+) (\S+) (\S+) (-|\[.*?\])(?: (.*))?$`)
+
+// SyslogParser decodes RFC 5424 formatted syslog lines.
+type SyslogParser struct{}
+
+func (p *SyslogParser) Parse(line []byte) (map[string]any, error) {
+	m := syslogRFC5424.FindSubmatch(line)
+	if m == nil {
+		return nil, fmt.Errorf("line does not match RFC5424 syslog format")
+	}
+
+	pri, err := strconv.Atoi(string(m[1]))
+	if err != nil {
+		return nil, fmt.Errorf("parse pri: %v", err)
+	}
+
+	fields := map[string]any{
+		"facility":        pri / 8,
+		"severity":        pri % 8,
+		"version":         string(m[2]),
+		"timestamp":       string(m[3]),
+		"hostname":        string(m[4]),
+		"app_name":        string(m[5]),
+		"procid":          string(m[6]),
+		"msgid":           string(m[7]),
+		"structured_data": string(m[8]),
+		"message":         string(m[9]),
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSyslogParser_Parse(t *testing.T) {
+	cases := []struct {
+		name    string
+		line    string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "valid RFC5424 line with message",
+			line: `<34>1 2023-10-10T22:14:15.003Z mymachine.example.com su - ID47 - BOM'su root' failed for lonvick`,
+			want: map[string]any{
+				"facility":        4,
+				"severity":        2,
+				"version":         "1",
+				"timestamp":       "2023-10-10T22:14:15.003Z",
+				"hostname":        "mymachine.example.com",
+				"app_name":        "su",
+				"procid":          "-",
+				"msgid":           "ID47",
+				"structured_data": "-",
+				"message":         "BOM'su root' failed for lonvick",
+			},
+		},
+		{
+			name: "no message",
+			line: `<165>1 2023-10-10T22:14:15.003Z host app 1234 ID1 -`,
+			want: map[string]any{
+				"facility":        20,
+				"severity":        5,
+				"version":         "1",
+				"timestamp":       "2023-10-10T22:14:15.003Z",
+				"hostname":        "host",
+				"app_name":        "app",
+				"procid":          "1234",
+				"msgid":           "ID1",
+				"structured_data": "-",
+				"message":         "",
+			},
+		},
+		{
+			name:    "non-matching line",
+			line:    "this is not a syslog line",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &SyslogParser{}
+
+			got, err := p.Parse([]byte(tc.line))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = nil error, want error", tc.line)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tc.line, err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Parse(%q) = %#v, want %#v", tc.line, got, tc.want)
+			}
+		})
+	}
+}
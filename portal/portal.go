@@ -1,14 +1,22 @@
 package portal
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
+
+	"git.uqpaytech.com/xufeng/log-portal/fetcher"
+	"git.uqpaytech.com/xufeng/log-portal/portal/parser"
+	"git.uqpaytech.com/xufeng/log-portal/sink"
 )
 
 const (
@@ -18,8 +26,19 @@ const (
 var (
 	location, _         = time.LoadLocation("Asia/Shanghai")
 	cleanInterval int64 = 3 // day
+
+	// nowFunc is the clock Clean measures archive age against; overridden in
+	// tests so Lifetime/size-eviction expiry doesn't depend on wall-clock
+	// time.
+	nowFunc = time.Now
 )
 
+// ArchiveUploader ships a rotated archive to long-term object storage
+// before Clean deletes it locally; see SetupRetention.
+type ArchiveUploader interface {
+	Upload(path string) error
+}
+
 type Portal struct {
 	// Remote file url.
 	URL string
@@ -36,12 +55,31 @@ type Portal struct {
 	// Archived local logs lifetime in days.
 	Lifetime int64
 
+	// Total size budget in bytes for archived logs under Dir; once
+	// exceeded, Clean evicts the oldest archives first regardless of
+	// Lifetime. <= 0 disables size-based eviction. See SetupRetention.
+	MaxTotalSize int64
+
+	// Compression applied to rotated archives in the background:
+	// CompressNone (default), CompressGzip, or CompressZstd. See
+	// SetupRetention.
+	CompressFormat string
+
 	// If output remote log into console.
 	ConsolePortal bool
 
 	// If write remote log into file.
 	FilePortal bool
 
+	// Output format for parsed lines: FormatRaw (default, bytes are copied
+	// through unchanged), FormatJSON, or FormatLogfmt. Only takes effect
+	// once a parser has been configured via SetupParser.
+	OutputFormat string
+
+	// Name of the registered parser.Parser used to decode lines, see
+	// SetupParser.
+	ParserName string
+
 	// Last remote log file size.
 	offset int64
 
@@ -53,9 +91,60 @@ type Portal struct {
 
 	writer io.Writer
 
-	client *http.Client
+	// fetcher reads from the remote source named by URL; its concrete type
+	// is picked by URL's scheme (http(s), sftp, s3, k8s) in Init. See
+	// SetClient for the one escape hatch that still reaches through to an
+	// http.Client directly.
+	fetcher fetcher.Fetcher
 
 	ticker *time.Ticker
+
+	// parser, when non-nil, decodes fetched lines into fields before they
+	// reach p.writer; see SetupParser.
+	parser parser.Parser
+
+	includeFields map[string]bool
+	excludeFields map[string]bool
+
+	// sinks receive the same bytes as the console/file writers, shipped
+	// out asynchronously; see SetupSinks.
+	sinks []sink.Sink
+
+	// lineBuf holds a partial trailing line carried over between
+	// FetchIncrContent calls, since a fetch boundary may land mid-line.
+	lineBuf []byte
+
+	// stop, when closed, causes Start to return once its current
+	// iteration finishes; see Stop.
+	stop chan struct{}
+
+	// Cumulative counters read by Manager to publish per-source metrics;
+	// always accessed via atomic so they're safe to read concurrently
+	// with Start's goroutine.
+	bytesFetched int64
+	fetchErrors  int64
+	rotations    int64
+	remoteSize   int64
+
+	// Last observed version token for the remote file (an ETag, generation,
+	// mtime, ... depending on the Fetcher), persisted to the checkpoint
+	// alongside offset; see resumeCheckpoint/saveCheckpoint.
+	etag string
+
+	// verifyInterval is how many successful fetch ticks elapse between
+	// content-integrity verification passes; 0 disables it. See
+	// EnableVerification.
+	verifyInterval int64
+	verifyTicks    int64
+	tail           *tailWindow
+
+	// uploader, when non-nil, ships an archive's path to long-term storage
+	// before Clean deletes it locally; see SetupRetention.
+	uploader ArchiveUploader
+
+	// compressQueue carries rotated archive paths to the background
+	// compression worker started by Start when CompressFormat is set.
+	compressQueue chan string
 }
 
 func New(url string, interval int64, tail int64) *Portal {
@@ -63,9 +152,122 @@ func New(url string, interval int64, tail int64) *Portal {
 		URL:           url,
 		Tail:          tail,
 		Interval:      interval,
-		client:        http.DefaultClient,
 		ConsolePortal: true,
+		stop:          make(chan struct{}),
+		OutputFormat:  FormatRaw,
+		compressQueue: make(chan string, 16),
+	}
+}
+
+// SetupRetention configures size-based eviction and background compression
+// of rotated archives. maxTotalSize <= 0 disables size-based eviction
+// (Lifetime-based expiry still applies). compressFormat is one of "",
+// CompressNone, CompressGzip, or CompressZstd. uploader, if non-nil, is
+// invoked with each archive's path before Clean deletes it, so it can be
+// shipped to object storage first; a failed upload skips the deletion
+// rather than losing the archive.
+func (p *Portal) SetupRetention(maxTotalSize int64, compressFormat string, uploader ArchiveUploader) error {
+	switch compressFormat {
+	case "", CompressNone, CompressGzip, CompressZstd:
+	default:
+		return fmt.Errorf("unsupported compress format: %s", compressFormat)
+	}
+
+	p.MaxTotalSize = maxTotalSize
+	p.CompressFormat = compressFormat
+	p.uploader = uploader
+
+	return nil
+}
+
+// SetClient overrides the http.Client used for requests, e.g. so a
+// portal.Manager can share one client with connection pooling across many
+// Portals. Only takes effect for URL schemes backed by fetcher.HTTPFetcher
+// (the default for a plain or http(s):// URL); it's a no-op for other
+// source types such as sftp:// or s3://. Must be called after Init().
+func (p *Portal) SetClient(client *http.Client) {
+	if hf, ok := p.fetcher.(*fetcher.HTTPFetcher); ok {
+		hf.SetClient(client)
+	}
+}
+
+// Stop signals Start to return once its current fetch iteration completes.
+// Safe to call once (it closes p.stop). Finalize does not call it; a Manager
+// supervising many Portals calls it directly for graceful shutdown.
+func (p *Portal) Stop() {
+	close(p.stop)
+}
+
+// BytesFetched returns the cumulative number of bytes copied from the
+// remote source since Start began.
+func (p *Portal) BytesFetched() int64 {
+	return atomic.LoadInt64(&p.bytesFetched)
+}
+
+// FetchErrors returns the cumulative number of failed size/fetch requests.
+func (p *Portal) FetchErrors() int64 {
+	return atomic.LoadInt64(&p.fetchErrors)
+}
+
+// Rotations returns the cumulative number of local file rotations.
+func (p *Portal) Rotations() int64 {
+	return atomic.LoadInt64(&p.rotations)
+}
+
+// Lag returns the difference between the last observed remote size and the
+// local offset, i.e. how many bytes Start has not yet caught up on.
+func (p *Portal) Lag() int64 {
+	return atomic.LoadInt64(&p.remoteSize) - atomic.LoadInt64(&p.offset)
+}
+
+// SetupParser configures structured parsing of fetched log lines. It must be
+// called after Init() and before Start(). format selects how parsed fields
+// are re-emitted (FormatJSON or FormatLogfmt); parserName selects the
+// registered parser.Parser (e.g. "nginx", "apache", "syslog"), or the
+// special name "regexp" for a generic parser.RegexParser built from
+// pattern (ignored for every other parserName); includeFields/excludeFields
+// restrict which parsed fields are kept, and are mutually applicable
+// (include narrows first, exclude then removes).
+func (p *Portal) SetupParser(format string, parserName string, pattern string, includeFields []string, excludeFields []string) error {
+	if format != FormatJSON && format != FormatLogfmt {
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+
+	var prs parser.Parser
+	var err error
+
+	if parserName == "regexp" {
+		if pattern == "" {
+			return fmt.Errorf("--parser regexp requires --parser-pattern")
+		}
+		prs, err = parser.NewRegexParser(pattern)
+	} else {
+		prs, err = parser.New(parserName)
+	}
+	if err != nil {
+		return err
+	}
+
+	p.OutputFormat = format
+	p.ParserName = parserName
+	p.parser = prs
+
+	if len(includeFields) > 0 {
+		p.includeFields = toSet(includeFields)
 	}
+	if len(excludeFields) > 0 {
+		p.excludeFields = toSet(excludeFields)
+	}
+
+	return nil
+}
+
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
 }
 
 func (p *Portal) Init() error {
@@ -77,21 +279,22 @@ func (p *Portal) Init() error {
 
 	p.filename = filename
 
+	f, err := fetcher.New(p.URL)
+	if err != nil {
+		return fmt.Errorf("build fetcher: %v", err)
+	}
+
+	p.fetcher = f
+
 	return nil
 }
 
 // SetupWriter must be called after Init().
 func (p *Portal) SetupWriter(disableConsole bool, enableFile bool, dir string, lifetime int64) error {
-	var writers []io.Writer
-
 	if disableConsole {
 		p.ConsolePortal = false
 	}
 
-	if p.ConsolePortal {
-		writers = append(writers, os.Stdout)
-	}
-
 	if enableFile {
 		if dir == "" {
 			return fmt.Errorf("invalid log dir[%s]", dir)
@@ -113,15 +316,117 @@ func (p *Portal) SetupWriter(disableConsole bool, enableFile bool, dir string, l
 		p.Lifetime = lifetime
 		p.FilePortal = true
 
-		writers = append(writers, p.file)
+		if err := p.resumeCheckpoint(); err != nil {
+			return fmt.Errorf("resume checkpoint: %v", err)
+		}
 	}
 
-	multiWriter := io.MultiWriter(writers...)
-	p.writer = multiWriter
+	p.rebuildWriter()
 
 	return nil
 }
 
+// resumeCheckpoint loads <Dir>/.portal-state.json, if any, and restores
+// p.offset so Start continues where the last run left off instead of
+// re-fetching the tail. If the remote version token has changed since the
+// checkpoint was written but the size hasn't shrunk below the checkpointed
+// offset, the source was replaced in place (no size-based rotation would
+// otherwise be detected), so the local file is rotated and the offset reset
+// to 0.
+func (p *Portal) resumeCheckpoint() error {
+	state, err := readCheckpointFile(p.Dir)
+	if err != nil {
+		return err
+	}
+
+	if state == nil || state.Filename != p.filename {
+		return nil
+	}
+
+	currSize, etag, err := p.stat()
+	if err != nil {
+		return fmt.Errorf("stat remote file: %v", err)
+	}
+
+	replaced := state.ETag != "" && etag != "" && state.ETag != etag
+
+	if replaced && currSize >= state.Offset {
+		if err := p.RotateFile(); err != nil {
+			return fmt.Errorf("rotate on checkpoint mismatch: %v", err)
+		}
+
+		fmt.Printf("[Portal]: remote file changed without shrinking, rotated local file\n")
+
+		return nil
+	}
+
+	atomic.StoreInt64(&p.offset, state.Offset)
+	p.etag = etag
+
+	fmt.Printf("[Portal]: resuming from checkpoint at offset %d\n", state.Offset)
+
+	return nil
+}
+
+// saveCheckpoint persists the current offset/version token so a restart can
+// resume from here. Failures are logged, not fatal: losing a checkpoint
+// write degrades to the old re-fetch-the-tail behavior, it doesn't corrupt
+// anything.
+func (p *Portal) saveCheckpoint() {
+	if !p.FilePortal {
+		return
+	}
+
+	state := checkpointState{
+		Offset:   atomic.LoadInt64(&p.offset),
+		Filename: p.filename,
+		ETag:     p.etag,
+	}
+
+	if err := writeCheckpointFile(p.Dir, state); err != nil {
+		fmt.Printf("save checkpoint: %v\n", err)
+	}
+}
+
+// SetupSinks constructs a Sink for each rawURL (scheme selects the
+// implementation: loki://, es://, kafka://, s3://) and fans output out to
+// it alongside the console/file writers. Must be called after SetupWriter.
+func (p *Portal) SetupSinks(rawURLs []string) error {
+	for _, rawURL := range rawURLs {
+		s, err := sink.New(rawURL)
+		if err != nil {
+			return fmt.Errorf("setup sink[%s]: %v", rawURL, err)
+		}
+
+		p.sinks = append(p.sinks, s)
+	}
+
+	p.rebuildWriter()
+
+	return nil
+}
+
+// rebuildWriter recomposes p.writer from the current console/file/sink
+// state. Called whenever any of those change (SetupWriter, SetupSinks,
+// RotateFile).
+func (p *Portal) rebuildWriter() {
+	var writers []io.Writer
+
+	if p.ConsolePortal {
+		writers = append(writers, os.Stdout)
+	}
+
+	if p.FilePortal {
+		writers = append(writers, p.file)
+	}
+
+	for _, s := range p.sinks {
+		writers = append(writers, s)
+	}
+
+	p.writer = io.MultiWriter(writers...)
+}
+
 func (p *Portal) LogName() string {
 	fields := strings.Split(p.URL, "/")
 	if len(fields) > 0 {
@@ -131,22 +436,21 @@ func (p *Portal) LogName() string {
 }
 
 func (p *Portal) LogSize() (int64, error) {
-	req, reqErr := http.NewRequest(http.MethodHead, p.URL, nil)
-	if reqErr != nil {
-		return 0, fmt.Errorf("create new request: %v", reqErr)
-	}
-
-	resp, doErr := p.client.Do(req)
-	if doErr != nil {
-		return 0, fmt.Errorf("http head: %v", doErr)
-	}
-	defer resp.Body.Close()
+	size, _, err := p.stat()
+	return size, err
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+// stat asks p.fetcher for the remote source's current size and version
+// token (the token may be empty if the source doesn't expose one), used by
+// the checkpoint/resume logic to detect out-of-band replacement of the
+// remote file.
+func (p *Portal) stat() (int64, string, error) {
+	size, etag, err := p.fetcher.Size(context.Background())
+	if err != nil {
+		return 0, "", fmt.Errorf("fetcher size: %v", err)
 	}
 
-	return resp.ContentLength, nil
+	return size, etag, nil
 }
 
 func (p *Portal) initialFetch() (int64, error) {
@@ -172,32 +476,92 @@ func (p *Portal) initialFetch() (int64, error) {
 	return currSize, nil
 }
 
+// FetchIncrContent fetches [start, end) and writes it out through
+// processChunk/emitLine, which split it on lines before writing — including
+// in raw/no-parser mode. That's a deliberate departure from a plain
+// byte-for-byte io.Copy: a fetch boundary very commonly lands mid-line, so
+// without splitting, sinks would see one record spanning several lines (or
+// several records glued into one). The cost is that a chunk's trailing
+// partial line is now held in p.lineBuf and not written until the next
+// successful fetch completes it, instead of being forwarded immediately.
 func (p *Portal) FetchIncrContent(start int64, end int64) error {
-	req, reqErr := http.NewRequest(http.MethodGet, p.URL, nil)
-	if reqErr != nil {
-		return fmt.Errorf("create new request: %v", reqErr)
+	var buf bytes.Buffer
+	if err := p.fetcher.ReadRange(context.Background(), start, end, &buf); err != nil {
+		return fmt.Errorf("fetch range: %v", err)
 	}
 
-	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+	chunk := buf.Bytes()
 
-	resp, doErr := p.client.Do(req)
-	if doErr != nil {
-		return fmt.Errorf("http get: %v", doErr)
+	atomic.AddInt64(&p.bytesFetched, int64(len(chunk)))
+
+	if p.tail != nil {
+		_, _ = p.tail.Write(chunk)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusPartialContent {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	return p.processChunk(chunk)
+}
+
+// processChunk appends chunk to any partial line held over from the
+// previous fetch, then emits every complete line it now contains (parsed,
+// if a parser is configured, otherwise unchanged). Splitting on lines here,
+// rather than writing the chunk through as one blob, keeps every Write to
+// p.writer a single record, which line-oriented sinks (ES bulk, Loki, ...)
+// depend on. A trailing line with no terminating newline is held in
+// p.lineBuf until the next call.
+func (p *Portal) processChunk(chunk []byte) error {
+	p.lineBuf = append(p.lineBuf, chunk...)
+
+	for {
+		idx := bytes.IndexByte(p.lineBuf, '\n')
+		if idx < 0 {
+			break
+		}
+
+		line := p.lineBuf[:idx]
+		p.lineBuf = p.lineBuf[idx+1:]
+
+		if err := p.emitLine(line); err != nil {
+			fmt.Printf("parse line: %v\n", err)
+		}
 	}
 
-	if _, err := io.Copy(p.writer, resp.Body); err != nil {
-		return fmt.Errorf("copy response body: %v", err)
+	return nil
+}
+
+func (p *Portal) emitLine(line []byte) error {
+	if p.parser == nil {
+		out := append(append([]byte(nil), line...), '\n')
+		if _, err := p.writer.Write(out); err != nil {
+			return fmt.Errorf("write output: %v", err)
+		}
+		return nil
+	}
+
+	fields, err := p.parser.Parse(line)
+	if err != nil {
+		return fmt.Errorf("parse line: %v", err)
+	}
+
+	fields = filterFields(fields, p.includeFields, p.excludeFields)
+
+	out, err := encodeFields(p.OutputFormat, fields)
+	if err != nil {
+		return fmt.Errorf("encode fields: %v", err)
+	}
+
+	if _, err := p.writer.Write(out); err != nil {
+		return fmt.Errorf("write output: %v", err)
 	}
 
 	return nil
 }
 
 func (p *Portal) RotateFile() error {
+	// Whatever trailing partial line is buffered belongs to the old remote
+	// file; prepending it to the new file's first bytes would corrupt a
+	// record, so it's discarded on every rotation, not just file rotations.
+	p.lineBuf = nil
+
 	if !p.FilePortal {
 		return nil
 	}
@@ -234,47 +598,67 @@ func (p *Portal) RotateFile() error {
 
 	p.file = newFile
 
-	writers := []io.Writer{p.file}
-	if p.ConsolePortal {
-		writers = append(writers, os.Stdout)
+	p.rebuildWriter()
+
+	if p.CompressFormat != "" && p.CompressFormat != CompressNone {
+		select {
+		case p.compressQueue <- archivedPath:
+		default:
+			fmt.Printf("compress queue full, leaving %s uncompressed\n", archivedPath)
+		}
 	}
-	p.writer = io.MultiWriter(writers...)
 
 	return nil
 }
 
-func (p *Portal) Clean() error {
-	match := func(filename string) bool {
-		now := time.Now().In(location)
-
-		fields1 := strings.SplitN(filename, "-", 2)
-		if len(fields1) != 2 {
-			return false
-		}
-
-		fn := fields1[1]
+// archiveSuffixes lists recognized archive extensions, longest first so a
+// compressed name like "access-<ts>.log.gz" isn't mistaken for ending in
+// plain ".log".
+var archiveSuffixes = []string{".log.zst", ".log.gz", ".log"}
+
+// archiveTimestamp extracts the rotation time embedded in an archive
+// filename produced by RotateFile ("<prefix>-<timestamp>.log[.gz|.zst]"),
+// reporting ok=false for anything else found in Dir.
+func archiveTimestamp(filename string) (t time.Time, ok bool) {
+	fields := strings.SplitN(filename, "-", 2)
+	if len(fields) != 2 {
+		return time.Time{}, false
+	}
 
-		suffix := ".log"
+	rest := fields[1]
 
-		if !strings.HasSuffix(fn, suffix) {
-			return false
+	for _, suffix := range archiveSuffixes {
+		if !strings.HasSuffix(rest, suffix) {
+			continue
 		}
 
-		prefix := fn[:len(fn)-len(suffix)]
+		prefix := rest[:len(rest)-len(suffix)]
 
 		t, err := time.ParseInLocation(timeLayout, prefix, location)
 		if err != nil {
-			return false
+			return time.Time{}, false
 		}
 
-		if now.UnixMilli()-t.UnixMilli() < p.Lifetime*24*60*60*1000 {
-			return false
-		}
+		return t, true
+	}
+
+	return time.Time{}, false
+}
 
-		return true
+// Clean removes archives older than Lifetime, then, if MaxTotalSize is set,
+// evicts the oldest remaining archives until the total is back under
+// budget. Each removal goes through removeArchive so a configured
+// ArchiveUploader gets a chance to ship the file first.
+func (p *Portal) Clean() error {
+	type archive struct {
+		path    string
+		size    int64
+		modTime time.Time
 	}
 
-	// 使用 filepath.WalkDir 遍历目录
+	now := nowFunc().In(location)
+	var kept []archive
+
 	err := filepath.WalkDir(p.Dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -289,24 +673,72 @@ func (p *Portal) Clean() error {
 			return nil
 		}
 
-		if !match(filename) {
+		t, ok := archiveTimestamp(filename)
+		if !ok {
 			return nil
 		}
 
-		if osErr := os.Remove(filename); osErr != nil {
-			return osErr
+		if now.UnixMilli()-t.UnixMilli() >= p.Lifetime*24*60*60*1000 {
+			return p.removeArchive(path)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
 		}
 
+		kept = append(kept, archive{path: path, size: info.Size(), modTime: info.ModTime()})
+
 		return nil
 	})
-
 	if err != nil {
 		return err
 	}
 
+	if p.MaxTotalSize <= 0 {
+		return nil
+	}
+
+	var total int64
+	for _, a := range kept {
+		total += a.size
+	}
+
+	if total <= p.MaxTotalSize {
+		return nil
+	}
+
+	sort.Slice(kept, func(i, j int) bool { return kept[i].modTime.Before(kept[j].modTime) })
+
+	for _, a := range kept {
+		if total <= p.MaxTotalSize {
+			break
+		}
+
+		if err := p.removeArchive(a.path); err != nil {
+			return err
+		}
+
+		total -= a.size
+	}
+
 	return nil
 }
 
+// removeArchive uploads path via p.uploader (if configured) before
+// deleting it; a failed upload skips the deletion so the archive isn't
+// lost.
+func (p *Portal) removeArchive(path string) error {
+	if p.uploader != nil {
+		if err := p.uploader.Upload(path); err != nil {
+			fmt.Printf("upload archive[%s]: %v, skipping deletion\n", path, err)
+			return nil
+		}
+	}
+
+	return os.Remove(path)
+}
+
 func (p *Portal) Start() {
 	p.ticker = time.NewTicker(time.Duration(p.Interval) * time.Second)
 	defer p.ticker.Stop()
@@ -325,44 +757,97 @@ func (p *Portal) Start() {
 			ticker := time.NewTicker(time.Second * 10)
 			defer ticker.Stop()
 
-			for range ticker.C {
-				_ = p.Clean()
+			for {
+				select {
+				case <-p.stop:
+					return
+				case <-ticker.C:
+					_ = p.Clean()
+				}
 			}
 		}()
+
+		if p.CompressFormat != "" && p.CompressFormat != CompressNone {
+			go p.runCompressionWorker()
+		}
 	}
 
-	currSize, getErr = p.initialFetch()
-	if getErr != nil {
-		fmt.Printf("initial fetch: %v\n", getErr)
+	if atomic.LoadInt64(&p.offset) == 0 {
+		currSize, getErr = p.initialFetch()
+		if getErr != nil {
+			fmt.Printf("initial fetch: %v\n", getErr)
+		}
+
+		if currSize > 0 {
+			atomic.StoreInt64(&p.offset, currSize)
+			p.saveCheckpoint()
+		}
 	}
 
-	for range p.ticker.C {
-		currSize, getErr = p.LogSize()
+	var etag string
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-p.ticker.C:
+		}
+
+		currSize, etag, getErr = p.stat()
 		if getErr != nil {
 			fmt.Printf("get log size: %v\n", getErr)
+			atomic.AddInt64(&p.fetchErrors, 1)
 			continue
 		}
 
-		if currSize > p.offset {
-			if err := p.FetchIncrContent(p.offset, currSize); err != nil {
+		atomic.StoreInt64(&p.remoteSize, currSize)
+		p.etag = etag
+
+		if offset := atomic.LoadInt64(&p.offset); currSize > offset {
+			if err := p.FetchIncrContent(offset, currSize); err != nil {
 				fmt.Printf("fetch incremental content: %v\n", err)
+				atomic.AddInt64(&p.fetchErrors, 1)
 				continue
 			}
 
-			p.offset = currSize
+			atomic.StoreInt64(&p.offset, currSize)
+			p.saveCheckpoint()
+
+			if p.verifyInterval > 0 {
+				p.verifyTicks++
+				if p.verifyTicks >= p.verifyInterval {
+					p.verifyTicks = 0
+
+					if err := p.verifyIntegrity(); err != nil {
+						fmt.Printf("content verification failed, treating as out-of-band rotation: %v\n", err)
+
+						if err := p.RotateFile(); err != nil {
+							fmt.Printf("rotate file: %v\n", err)
+						} else {
+							atomic.AddInt64(&p.rotations, 1)
+						}
+
+						atomic.StoreInt64(&p.offset, 0)
+						p.saveCheckpoint()
+					}
+				}
+			}
+
 			continue
 		}
 
-		if currSize < p.offset {
+		if currSize < atomic.LoadInt64(&p.offset) {
 			// remote log rotated
 			if err := p.RotateFile(); err != nil {
 				fmt.Printf("rotate file: %v\n", err)
+			} else {
+				atomic.AddInt64(&p.rotations, 1)
 			}
 
-			p.offset = 0
+			atomic.StoreInt64(&p.offset, 0)
+			p.saveCheckpoint()
 		}
 	}
-
 }
 
 func (p *Portal) Finalize() {
@@ -372,5 +857,9 @@ func (p *Portal) Finalize() {
 	_ = p.file.Sync()
 	_ = p.file.Close()
 
+	for _, s := range p.sinks {
+		_ = s.Close()
+	}
+
 	p.ticker.Stop()
 }
@@ -0,0 +1,121 @@
+package portal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeNow overrides nowFunc for the duration of the test so Clean's
+// Lifetime/size-eviction checks don't depend on wall-clock time.
+func fakeNow(t *testing.T, at time.Time) {
+	t.Helper()
+
+	orig := nowFunc
+	nowFunc = func() time.Time { return at }
+	t.Cleanup(func() { nowFunc = orig })
+}
+
+// writeArchive creates an archive file named the way RotateFile names them
+// ("<prefix>-<timestamp>.log"), so archiveTimestamp can parse it back out.
+func writeArchive(t *testing.T, dir string, ts time.Time, size int) string {
+	t.Helper()
+
+	name := "access-" + ts.In(location).Format(timeLayout) + ".log"
+	path := filepath.Join(dir, name)
+
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatalf("write archive[%s]: %v", path, err)
+	}
+
+	return path
+}
+
+func TestClean_ExpiresArchivesOlderThanLifetime(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, location)
+	fakeNow(t, now)
+
+	oldPath := writeArchive(t, dir, now.Add(-4*24*time.Hour), 10)
+	newPath := writeArchive(t, dir, now.Add(-1*24*time.Hour), 10)
+
+	p := &Portal{Dir: dir, filename: "access.log", Lifetime: 3}
+
+	if err := p.Clean(); err != nil {
+		t.Fatalf("Clean: %v", err)
+	}
+
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Errorf("expected expired archive %s to be removed, stat err = %v", oldPath, err)
+	}
+
+	if _, err := os.Stat(newPath); err != nil {
+		t.Errorf("expected archive within lifetime %s to survive, stat err = %v", newPath, err)
+	}
+}
+
+func TestClean_KeepsCurrentLogFileRegardlessOfAge(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, location)
+	fakeNow(t, now)
+
+	currentPath := filepath.Join(dir, "access.log")
+	if err := os.WriteFile(currentPath, []byte("still being written"), 0644); err != nil {
+		t.Fatalf("write current log: %v", err)
+	}
+
+	p := &Portal{Dir: dir, filename: "access.log", Lifetime: 1}
+
+	if err := p.Clean(); err != nil {
+		t.Fatalf("Clean: %v", err)
+	}
+
+	if _, err := os.Stat(currentPath); err != nil {
+		t.Errorf("expected current log file to survive Clean, stat err = %v", err)
+	}
+}
+
+func TestClean_EvictsOldestArchivesWhenOverSizeBudget(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, location)
+	fakeNow(t, now)
+
+	// All within Lifetime, so only the size budget should drive eviction.
+	oldest := writeArchive(t, dir, now.Add(-3*time.Hour), 100)
+	middle := writeArchive(t, dir, now.Add(-2*time.Hour), 100)
+	newest := writeArchive(t, dir, now.Add(-1*time.Hour), 100)
+
+	// archiveTimestamp drives Lifetime expiry, but eviction order within
+	// Clean's size pass is based on file mtime, so set that explicitly in
+	// the same oldest-to-newest order as the embedded timestamps.
+	setModTime(t, oldest, now.Add(-3*time.Hour))
+	setModTime(t, middle, now.Add(-2*time.Hour))
+	setModTime(t, newest, now.Add(-1*time.Hour))
+
+	p := &Portal{Dir: dir, filename: "access.log", Lifetime: 30, MaxTotalSize: 250}
+
+	if err := p.Clean(); err != nil {
+		t.Fatalf("Clean: %v", err)
+	}
+
+	if _, err := os.Stat(oldest); !os.IsNotExist(err) {
+		t.Errorf("expected oldest archive %s to be evicted over budget, stat err = %v", oldest, err)
+	}
+
+	if _, err := os.Stat(middle); err != nil {
+		t.Errorf("expected middle archive %s to survive, stat err = %v", middle, err)
+	}
+
+	if _, err := os.Stat(newest); err != nil {
+		t.Errorf("expected newest archive %s to survive, stat err = %v", newest, err)
+	}
+}
+
+func setModTime(t *testing.T, path string, mtime time.Time) {
+	t.Helper()
+
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatalf("chtimes[%s]: %v", path, err)
+	}
+}
@@ -0,0 +1,92 @@
+package portal
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync/atomic"
+)
+
+// verifyWindowSize is how many trailing bytes are kept locally and
+// re-probed against the remote source on each verification pass.
+const verifyWindowSize = 4096
+
+// tailWindow is a fixed-size ring buffer of the most recently written
+// bytes, used to compare what was written locally against a Range re-fetch
+// of the same remote window.
+type tailWindow struct {
+	buf  []byte
+	size int
+}
+
+func newTailWindow(size int) *tailWindow {
+	return &tailWindow{buf: make([]byte, 0, size), size: size}
+}
+
+func (w *tailWindow) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	if len(w.buf) > w.size {
+		w.buf = w.buf[len(w.buf)-w.size:]
+	}
+	return len(p), nil
+}
+
+// Bytes returns the current window contents; only valid until the next
+// Write.
+func (w *tailWindow) Bytes() []byte {
+	return w.buf
+}
+
+// EnableVerification turns on periodic content-integrity verification:
+// every interval successful fetch ticks, Start re-requests the last
+// verifyWindowSize bytes it wrote and confirms they still match what the
+// remote source reports for that range. interval <= 0 disables
+// verification (the default).
+func (p *Portal) EnableVerification(interval int64) {
+	p.verifyInterval = interval
+	if p.tail == nil {
+		p.tail = newTailWindow(verifyWindowSize)
+	}
+}
+
+// verifyIntegrity re-fetches the last verifyWindowSize bytes written and
+// compares their SHA-256 against what Start most recently wrote for that
+// range. A mismatch means the remote file was replaced out-of-band without
+// Content-Length shrinking below p.offset, which the normal
+// currSize-vs-offset rotation check can't catch.
+func (p *Portal) verifyIntegrity() error {
+	window := p.tail.Bytes()
+	if len(window) == 0 {
+		return nil
+	}
+
+	end := atomic.LoadInt64(&p.offset)
+	start := end - int64(len(window))
+	if start < 0 {
+		return nil
+	}
+
+	remote, err := p.fetchRange(start, end)
+	if err != nil {
+		return fmt.Errorf("fetch verification range: %v", err)
+	}
+
+	if sha256.Sum256(remote) != sha256.Sum256(window) {
+		return fmt.Errorf("local and remote bytes diverge for range [%d-%d)", start, end)
+	}
+
+	return nil
+}
+
+// fetchRange reads [start, end) from the remote source's Fetcher and
+// returns the raw bytes, without writing them anywhere else.
+func (p *Portal) fetchRange(start int64, end int64) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := p.fetcher.ReadRange(context.Background(), start, end, &buf); err != nil {
+		return nil, fmt.Errorf("fetch range: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,142 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultQueueSize     = 1024
+	defaultBatchSize     = 100
+	defaultFlushInterval = 2 * time.Second
+	defaultMaxRetries    = 5
+	defaultBackoff       = 500 * time.Millisecond
+)
+
+// flushFunc ships one batch of written records to the destination. It
+// should return an error only for failures base should retry; base makes up
+// to defaultMaxRetries attempts in all, with exponential backoff between
+// them, before giving up on the batch and logging it.
+type flushFunc func(batch [][]byte) error
+
+// base implements the bounded-queue/batch/retry/drop-oldest machinery
+// shared by every concrete Sink. Concrete sinks embed *base and supply a
+// flushFunc via newBase.
+type base struct {
+	queue chan []byte
+	flush flushFunc
+
+	closeOnce sync.Once
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+func newBase(name string, flush flushFunc) *base {
+	b := &base{
+		queue: make(chan []byte, defaultQueueSize),
+		flush: flush,
+		done:  make(chan struct{}),
+	}
+
+	b.wg.Add(1)
+	go b.run(name)
+
+	return b
+}
+
+// Write enqueues p for asynchronous delivery as one logical record. The
+// trailing newline Portal appends to keep console/file output
+// line-delimited is stripped here, since every concrete sink already
+// frames one record as one line/value/message and would otherwise ship a
+// trailing blank line (e.g. Elasticsearch's _bulk NDJSON). Write never
+// blocks on network I/O: if the queue is full, the oldest queued record is
+// dropped to make room.
+func (b *base) Write(p []byte) (int, error) {
+	record := bytes.TrimRight(append([]byte(nil), p...), "\n")
+
+	select {
+	case b.queue <- record:
+	default:
+		select {
+		case <-b.queue:
+		default:
+		}
+		select {
+		case b.queue <- record:
+		default:
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close stops accepting new work, flushes whatever is queued, and waits for
+// the background goroutine to exit.
+func (b *base) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.done)
+	})
+	b.wg.Wait()
+	return nil
+}
+
+func (b *base) run(name string) {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(defaultFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, defaultBatchSize)
+
+	flushBatch := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := b.flushWithRetry(batch); err != nil {
+			fmt.Printf("[sink:%s]: flush: %v\n", name, err)
+		}
+
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case record := <-b.queue:
+			batch = append(batch, record)
+			if len(batch) >= defaultBatchSize {
+				flushBatch()
+			}
+		case <-ticker.C:
+			flushBatch()
+		case <-b.done:
+			for {
+				select {
+				case record := <-b.queue:
+					batch = append(batch, record)
+				default:
+					flushBatch()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (b *base) flushWithRetry(batch [][]byte) error {
+	backoff := defaultBackoff
+
+	var err error
+	for attempt := 0; attempt < defaultMaxRetries; attempt++ {
+		if err = b.flush(batch); err == nil {
+			return nil
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %v", defaultMaxRetries, err)
+}
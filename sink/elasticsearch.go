@@ -0,0 +1,73 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func init() {
+	register("es", newElasticsearchSink)
+}
+
+// elasticsearchSink batches writes and ships them to Elasticsearch's bulk
+// API (_bulk), one index action per record.
+type elasticsearchSink struct {
+	*base
+	bulkEndpoint string
+	index        string
+	client       *http.Client
+}
+
+// newElasticsearchSink builds a sink from a URL of the form
+// es://host:port/index.
+func newElasticsearchSink(rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse es sink url: %v", err)
+	}
+
+	index := strings.TrimPrefix(u.Path, "/")
+	if index == "" {
+		index = "logs"
+	}
+
+	s := &elasticsearchSink{
+		bulkEndpoint: fmt.Sprintf("http://%s/_bulk", u.Host),
+		index:        index,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+	s.base = newBase("es", s.push)
+
+	return s, nil
+}
+
+func (s *elasticsearchSink) push(batch [][]byte) error {
+	var buf bytes.Buffer
+	for _, rec := range batch {
+		fmt.Fprintf(&buf, `{"index":{"_index":%q}}`+"\n", s.index)
+		buf.Write(rec)
+		buf.WriteByte('\n')
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.bulkEndpoint, &buf)
+	if err != nil {
+		return fmt.Errorf("create es request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("bulk index to elasticsearch: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected elasticsearch status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
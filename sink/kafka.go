@@ -0,0 +1,62 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func init() {
+	register("kafka", newKafkaSink)
+}
+
+// kafkaSink batches writes and publishes them to a Kafka topic via
+// segmentio/kafka-go.
+type kafkaSink struct {
+	*base
+	writer *kafka.Writer
+}
+
+// newKafkaSink builds a sink from a URL of the form
+// kafka://broker1,broker2:9092/topic.
+func newKafkaSink(rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse kafka sink url: %v", err)
+	}
+
+	topic := strings.TrimPrefix(u.Path, "/")
+	if topic == "" {
+		return nil, fmt.Errorf("kafka sink url must include a topic path, e.g. kafka://broker:9092/mytopic")
+	}
+
+	s := &kafkaSink{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(strings.Split(u.Host, ",")...),
+			Topic:    topic,
+			Balancer: &kafka.LeastBytes{},
+		},
+	}
+	s.base = newBase("kafka", s.push)
+
+	return s, nil
+}
+
+func (s *kafkaSink) push(batch [][]byte) error {
+	msgs := make([]kafka.Message, len(batch))
+	for i, rec := range batch {
+		msgs[i] = kafka.Message{Value: rec}
+	}
+
+	return s.writer.WriteMessages(context.Background(), msgs...)
+}
+
+func (s *kafkaSink) Close() error {
+	if err := s.base.Close(); err != nil {
+		return err
+	}
+	return s.writer.Close()
+}
@@ -0,0 +1,100 @@
+package sink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	register("loki", newLokiSink)
+}
+
+// lokiSink batches writes and pushes them to Loki's push API
+// (/loki/api/v1/push), gzip-compressed.
+type lokiSink struct {
+	*base
+	endpoint string
+	labels   map[string]string
+	client   *http.Client
+}
+
+// newLokiSink builds a sink from a URL of the form loki://host:port/path
+// with stream labels taken from the query string, e.g.
+// loki://localhost:3100?job=nginx&env=prod.
+func newLokiSink(rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse loki sink url: %v", err)
+	}
+
+	labels := map[string]string{"job": "log-portal"}
+	for k, v := range u.Query() {
+		if len(v) > 0 {
+			labels[k] = v[0]
+		}
+	}
+
+	s := &lokiSink{
+		endpoint: fmt.Sprintf("http://%s%s/loki/api/v1/push", u.Host, strings.TrimSuffix(u.Path, "/")),
+		labels:   labels,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+	s.base = newBase("loki", s.push)
+
+	return s, nil
+}
+
+func (s *lokiSink) push(batch [][]byte) error {
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	values := make([][2]string, len(batch))
+	for i, rec := range batch {
+		values[i] = [2]string{now, string(rec)}
+	}
+
+	payload := map[string]any{
+		"streams": []map[string]any{
+			{"stream": s.labels, "values": values},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal loki payload: %v", err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(body); err != nil {
+		return fmt.Errorf("gzip loki payload: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("close gzip writer: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, &buf)
+	if err != nil {
+		return fmt.Errorf("create loki request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("push to loki: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected loki status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func init() {
+	register("s3", newS3Sink)
+}
+
+// s3Sink batches writes and uploads each batch as a new, timestamped
+// object, so the remote bucket ends up with rotated chunks rather than one
+// ever-growing key.
+type s3Sink struct {
+	*base
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// newS3Sink builds a sink from a URL of the form s3://bucket/prefix.
+func newS3Sink(rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse s3 sink url: %v", err)
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %v", err)
+	}
+
+	s := &s3Sink{
+		client: s3.NewFromConfig(cfg),
+		bucket: u.Host,
+		prefix: strings.TrimPrefix(u.Path, "/"),
+	}
+	s.base = newBase("s3", s.push)
+
+	return s, nil
+}
+
+func (s *s3Sink) push(batch [][]byte) error {
+	var buf bytes.Buffer
+	for _, rec := range batch {
+		buf.Write(rec)
+		buf.WriteByte('\n')
+	}
+
+	key := fmt.Sprintf("%s%s.log", s.prefix, time.Now().Format("20060102T150405.000000000"))
+
+	_, err := s.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(buf.Bytes()),
+	})
+	if err != nil {
+		return fmt.Errorf("put object: %v", err)
+	}
+
+	return nil
+}
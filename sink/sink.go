@@ -0,0 +1,46 @@
+// Package sink ships parsed log output to external destinations (Loki,
+// Elasticsearch, Kafka, S3) concurrently with the console/file writers in
+// package portal.
+package sink
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// Sink is an io.Writer that fans written bytes out to an external
+// destination on a background goroutine. Writes never block on network
+// I/O: they enqueue onto a bounded internal channel and return immediately,
+// dropping the oldest queued entry if the channel is full. Close must be
+// called to drain the queue and release the background goroutine.
+type Sink interface {
+	io.Writer
+	io.Closer
+}
+
+type constructor func(rawURL string) (Sink, error)
+
+var registry = map[string]constructor{}
+
+// register makes a sink constructor available under a URL scheme. Called
+// from the init() function of each concrete sink implementation.
+func register(scheme string, newFunc constructor) {
+	registry[scheme] = newFunc
+}
+
+// New parses rawURL's scheme (e.g. "loki://", "es://", "kafka://", "s3://")
+// and constructs the matching Sink.
+func New(rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse sink url: %v", err)
+	}
+
+	newFunc, ok := registry[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sink scheme: %s", u.Scheme)
+	}
+
+	return newFunc(rawURL)
+}